@@ -0,0 +1,149 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stats
+
+import (
+	"encoding/json"
+
+	"github.com/elastic/beats/libbeat/common"
+	"github.com/elastic/beats/metricbeat/helper/elastic"
+	"github.com/elastic/beats/metricbeat/mb"
+)
+
+// statusCodes maps Kibana's textual availability status to the numeric
+// code used by the plain (non-XPack) event, so users graphing this in
+// Prometheus/InfluxDB don't have to carry a string series around.
+var statusCodes = map[string]int64{
+	"green":  1,
+	"yellow": 2,
+	"red":    3,
+}
+
+// eventMapping builds the flat, non-XPack kibana.stats.* event emitted
+// when xpack.enabled is false. Unlike eventMappingXPack it does not get
+// indexed into .monitoring-kibana-*, so it is safe to ship through any
+// output.
+func eventMapping(r mb.ReporterV2, content []byte) error {
+	var data map[string]interface{}
+	if err := json.Unmarshal(content, &data); err != nil {
+		r.Error(err)
+		return err
+	}
+
+	kibana, ok := data["kibana"].(map[string]interface{})
+	if !ok {
+		return elastic.ReportErrorForMissingField("kibana", elastic.Kibana, r)
+	}
+
+	name, ok := kibana["name"].(string)
+	if !ok {
+		return elastic.ReportErrorForMissingField("kibana.name", elastic.Kibana, r)
+	}
+	uuid, ok := kibana["uuid"].(string)
+	if !ok {
+		return elastic.ReportErrorForMissingField("kibana.uuid", elastic.Kibana, r)
+	}
+	version, ok := kibana["version"].(string)
+	if !ok {
+		return elastic.ReportErrorForMissingField("kibana.version", elastic.Kibana, r)
+	}
+	status, ok := kibana["status"].(string)
+	if !ok {
+		return elastic.ReportErrorForMissingField("kibana.status", elastic.Kibana, r)
+	}
+
+	// From 6.4 onwards the runtime metrics live under a "metrics" object;
+	// older Kibana versions report them at the top level.
+	metrics := data
+	if m, ok := data["metrics"].(map[string]interface{}); ok {
+		metrics = m
+	}
+
+	process, ok := metrics["process"].(map[string]interface{})
+	if !ok {
+		return elastic.ReportErrorForMissingField("process", elastic.Kibana, r)
+	}
+	memory, ok := process["memory"].(map[string]interface{})
+	if !ok {
+		return elastic.ReportErrorForMissingField("process.memory", elastic.Kibana, r)
+	}
+	heap, ok := memory["heap"].(map[string]interface{})
+	if !ok {
+		return elastic.ReportErrorForMissingField("process.memory.heap", elastic.Kibana, r)
+	}
+
+	fields := common.MapStr{
+		"name":    name,
+		"uuid":    uuid,
+		"version": version,
+		// "status" is a group rather than a plain string so that the
+		// numeric status.code sits alongside the human-readable name,
+		// following the same dotted-path convention as heap.*,
+		// uptime.ms and response_time.*.
+		"status": common.MapStr{
+			"name": status,
+			"code": statusCodes[status],
+		},
+		"concurrent_connections": metrics["concurrent_connections"],
+		"uptime": common.MapStr{
+			"ms": process["uptime_ms"],
+		},
+		"heap": common.MapStr{
+			"total": common.MapStr{"bytes": heap["total_bytes"]},
+			"used":  common.MapStr{"bytes": heap["used_bytes"]},
+		},
+	}
+
+	if limit, ok := heap["size_limit"]; ok {
+		fields.Put("heap.limit.bytes", limit)
+	}
+
+	if responseTimes, ok := metrics["response_times"].(map[string]interface{}); ok {
+		responseTime := common.MapStr{}
+		if avg, ok := responseTimes["avg_ms"]; ok {
+			responseTime.Put("avg.ms", avg)
+		}
+		if max, ok := responseTimes["max_ms"]; ok {
+			responseTime.Put("max.ms", max)
+		}
+		if len(responseTime) > 0 {
+			fields["response_time"] = responseTime
+		}
+	}
+
+	if requests, ok := metrics["requests"].(map[string]interface{}); ok {
+		requestFields := common.MapStr{}
+		if total, ok := requests["total"]; ok {
+			requestFields["total"] = total
+		}
+		if disconnects, ok := requests["disconnects"]; ok {
+			requestFields["disconnects"] = disconnects
+		}
+		if len(requestFields) > 0 {
+			fields["requests"] = requestFields
+		}
+	}
+
+	event := mb.Event{
+		MetricSetFields: fields,
+	}
+
+	r.Event(event)
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stats
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/elastic/beats/libbeat/common"
+	s "github.com/elastic/beats/libbeat/common/schema"
+	c "github.com/elastic/beats/libbeat/common/schema/mapstriface"
+	"github.com/elastic/beats/metricbeat/helper/elastic"
+	"github.com/elastic/beats/metricbeat/mb"
+)
+
+// settingsSchema covers the handful of settings the Kibana monitoring UI
+// cares about today: whether a default admin email is configured for
+// alerting.
+var settingsSchema = s.Schema{
+	"xpack": c.Dict("xpack", s.Schema{
+		"default_admin_email": c.Str("default_admin_email", s.Optional),
+	}, c.DictOptional),
+}
+
+// eventMappingSettings builds the kibana_settings document that the
+// Kibana monitoring UI pairs with each kibana_stats document in the same
+// .monitoring-kibana-* index.
+func eventMappingSettings(r mb.ReporterV2, intervalMs int64, content []byte) error {
+	var data map[string]interface{}
+	if err := json.Unmarshal(content, &data); err != nil {
+		r.Error(err)
+		return err
+	}
+
+	settingsFields, err := settingsSchema.Apply(data)
+	if err != nil {
+		r.Error(err)
+		return err
+	}
+
+	clusterUUID, ok := data["cluster_uuid"].(string)
+	if !ok {
+		return elastic.ReportErrorForMissingField("cluster_uuid", elastic.Kibana, r)
+	}
+
+	timestamp := time.Now()
+	settingsFields.Put("timestamp", timestamp)
+
+	var event mb.Event
+	event.RootFields = common.MapStr{
+		"cluster_uuid":    clusterUUID,
+		"timestamp":       timestamp,
+		"interval_ms":     intervalMs,
+		"type":            "kibana_settings",
+		"kibana_settings": settingsFields,
+	}
+
+	event.Index = elastic.MakeXPackMonitoringIndexName(elastic.Kibana)
+	r.Event(event)
+
+	return nil
+}
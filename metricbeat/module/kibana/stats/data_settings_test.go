@@ -0,0 +1,49 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stats
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mbtest "github.com/elastic/beats/metricbeat/mb/testing"
+)
+
+func TestEventMappingSettings(t *testing.T) {
+	content, err := ioutil.ReadFile("./_meta/test/settings.json")
+	assert.NoError(t, err)
+
+	reporter := &mbtest.CapturingReporterV2{}
+	err = eventMappingSettings(reporter, 10000, content)
+	assert.NoError(t, err)
+	assert.Empty(t, reporter.GetErrors())
+
+	events := reporter.GetEvents()
+	assert.Len(t, events, 1)
+
+	event := events[0]
+	eventType, err := event.RootFields.GetValue("type")
+	assert.NoError(t, err)
+	assert.Equal(t, "kibana_settings", eventType)
+
+	email, err := event.RootFields.GetValue("kibana_settings.xpack.default_admin_email")
+	assert.NoError(t, err)
+	assert.Equal(t, "admin@example.com", email)
+}
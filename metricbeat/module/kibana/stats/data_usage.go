@@ -0,0 +1,140 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stats
+
+import (
+	"github.com/elastic/beats/libbeat/common"
+	s "github.com/elastic/beats/libbeat/common/schema"
+	c "github.com/elastic/beats/libbeat/common/schema/mapstriface"
+)
+
+// usageSchema covers the saved-object counters and the xpack.reporting
+// usage that have been part of Kibana's usage API since early on. These
+// keys get the same typed treatment as before; every other top-level
+// key of data["usage"], and every xpack.* sub-tree other than reporting,
+// is handled permissively by mapUsage. Keys under usage.kibana other
+// than the ones listed here are not passed through.
+var usageSchema = s.Schema{
+	"index": c.Str("kibana.index"),
+	"index_pattern": c.Dict("kibana.index_pattern", s.Schema{
+		"total": c.Int("total"),
+	}),
+	"search": c.Dict("kibana.search", s.Schema{
+		"total": c.Int("total"),
+	}),
+	"visualization": c.Dict("kibana.visualization", s.Schema{
+		"total": c.Int("total"),
+	}),
+	"dashboard": c.Dict("kibana.dashboard", s.Schema{
+		"total": c.Int("total"),
+	}),
+	"timelion_sheet": c.Dict("kibana.timelion_sheet", s.Schema{
+		"total": c.Int("total"),
+	}),
+	"graph_workspace": c.Dict("kibana.graph_workspace", s.Schema{
+		"total": c.Int("total"),
+	}),
+	"xpack": c.Dict("xpack", s.Schema{
+		"reporting": c.Dict("reporting", s.Schema{
+			"available":     c.Bool("available"),
+			"enabled":       c.Bool("enabled"),
+			"browser_type":  c.Str("browser_type"),
+			"_all":          c.Int("all"),
+			"csv":           reportingCsvDict,
+			"printable_pdf": reportingPrintablePdfDict,
+			"status":        reportingStatusDict,
+			"lastDay":       c.Dict("last_day", reportingPeriodSchema, c.DictOptional),
+			"last7Days":     c.Dict("last_7_days", reportingPeriodSchema, c.DictOptional),
+		}, c.DictOptional),
+	}, c.DictOptional),
+}
+
+// knownUsageKeys are the top-level data["usage"] keys already handled by
+// usageSchema above. Every other top-level key is passed through by
+// mapUsage instead of being silently dropped; under xpack, only the
+// sub-trees other than reporting are passed through.
+var knownUsageKeys = map[string]bool{
+	"kibana": true,
+	"xpack":  true,
+}
+
+// mapUsage builds the kibana_stats.usage.* fields. Kibana's usage API
+// keeps growing (Lens, Maps, Canvas, Alerting, Cases, ML, arbitrary
+// xpack.* plugin sub-trees, ...) and we don't want to chase every new
+// plugin with a schema change, so known counters still get typed
+// treatment via usageSchema, while anything else is walked recursively
+// and passed through as-is, coercing numeric leaves to int64.
+func mapUsage(data map[string]interface{}) (common.MapStr, error) {
+	usage, ok := data["usage"].(map[string]interface{})
+	if !ok {
+		return common.MapStr{}, nil
+	}
+
+	fields, err := usageSchema.Apply(usage)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range usage {
+		if knownUsageKeys[key] {
+			continue
+		}
+		fields[key] = normalizeUsageValue(value)
+	}
+
+	if xpack, ok := usage["xpack"].(map[string]interface{}); ok {
+		xpackFields, ok := fields["xpack"].(common.MapStr)
+		if !ok {
+			xpackFields = common.MapStr{}
+			fields["xpack"] = xpackFields
+		}
+		for key, value := range xpack {
+			if key == "reporting" {
+				continue
+			}
+			xpackFields[key] = normalizeUsageValue(value)
+		}
+	}
+
+	return fields, nil
+}
+
+// normalizeUsageValue recursively coerces a decoded JSON value into the
+// types Metricbeat events expect: every float64 leaf becomes int64 (any
+// fractional part is dropped), maps become common.MapStr, and everything else (string,
+// bool, already-int64, nil) passes through unchanged.
+func normalizeUsageValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := common.MapStr{}
+		for key, nested := range v {
+			out[key] = normalizeUsageValue(nested)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalizeUsageValue(item)
+		}
+		return out
+	case float64:
+		return int64(v)
+	default:
+		return v
+	}
+}
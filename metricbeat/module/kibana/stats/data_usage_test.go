@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stats
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapUsage(t *testing.T) {
+	raw := []byte(`{
+		"usage": {
+			"kibana": {
+				"index": ".kibana",
+				"index_pattern": {"total": 2},
+				"search": {"total": 1},
+				"visualization": {"total": 3},
+				"dashboard": {"total": 1},
+				"timelion_sheet": {"total": 0},
+				"graph_workspace": {"total": 0}
+			},
+			"lens": {
+				"events_27_days": 12,
+				"saved_overall": 4
+			},
+			"maps": {
+				"mapsTotalCount": 3
+			},
+			"xpack": {
+				"reporting": {
+					"available": true,
+					"enabled": true,
+					"browser_type": "chromium",
+					"_all": 7,
+					"csv": {"available": true, "total": 4}
+				}
+			}
+		}
+	}`)
+
+	var data map[string]interface{}
+	assert.NoError(t, json.Unmarshal(raw, &data))
+
+	fields, err := mapUsage(data)
+	assert.NoError(t, err)
+
+	total, err := fields.GetValue("index_pattern.total")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+
+	lensEvents, err := fields.GetValue("lens.events_27_days")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 12, lensEvents)
+
+	mapsTotal, err := fields.GetValue("maps.mapsTotalCount")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, mapsTotal)
+
+	// usage.xpack is a known key (it's where usageSchema pulls the typed
+	// "reporting" fields from) and must keep its typed treatment rather
+	// than being overwritten by the permissive passthrough.
+	reportingTotal, err := fields.GetValue("xpack.reporting.csv.total")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, reportingTotal)
+
+	browserType, err := fields.GetValue("xpack.reporting.browser_type")
+	assert.NoError(t, err)
+	assert.Equal(t, "chromium", browserType)
+}
@@ -19,6 +19,7 @@ package stats
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/elastic/beats/libbeat/common"
@@ -29,7 +30,30 @@ import (
 )
 
 var (
-	schemaXPackMonitoring = s.Schema{
+	// commonXPackSchema covers the parts of the stats payload that have
+	// been stable across the versions we support: the Kibana instance
+	// identity. This is never nested under the "metrics" wrapper that
+	// 6.4+ introduced. The usage sub-tree is handled separately by
+	// mapUsage, since it mixes a handful of known, typed counters with
+	// an open-ended set of plugin-contributed ones.
+	commonXPackSchema = s.Schema{
+		"kibana": c.Dict("kibana", s.Schema{
+			"uuid":              c.Str("uuid"),
+			"name":              c.Str("name"),
+			"index":             c.Str("index"),
+			"host":              c.Str("host"),
+			"transport_address": c.Str("transport_address"),
+			"version":           c.Str("version"),
+			"snapshot":          c.Bool("snapshot"),
+			"status":            c.Str("status"),
+		}),
+	}
+
+	// schema64 covers the "metrics" shape that Kibana settled on from
+	// 6.4 onwards: concurrent_connections, os and process metrics move
+	// under a "metrics" object, the heap reports total, used and
+	// size_limit bytes, and requests are reported as a running total.
+	schema64 = s.Schema{
 		"concurrent_connections": c.Int("concurrent_connections"),
 		"os": c.Dict("os", s.Schema{
 			"load": c.Dict("load", s.Schema{
@@ -55,54 +79,62 @@ var (
 			}),
 			"uptime_in_millis": c.Int("uptime_ms"),
 		}),
-		"requests": RequestsDict,
+		"requests": c.Dict("requests", s.Schema{
+			"total":       c.Int("total"),
+			"disconnects": c.Int("disconnects"),
+		}),
 		"response_times": c.Dict("response_times", s.Schema{
 			"average": c.Int("avg_ms", s.Optional),
 			"max":     c.Int("max_ms", s.Optional),
 		}, c.DictOptional),
-		"kibana": c.Dict("kibana", s.Schema{
-			"uuid":              c.Str("uuid"),
-			"name":              c.Str("name"),
-			"index":             c.Str("index"),
-			"host":              c.Str("host"),
-			"transport_address": c.Str("transport_address"),
-			"version":           c.Str("version"),
-			"snapshot":          c.Bool("snapshot"),
-			"status":            c.Str("status"),
+	}
+
+	// schema67 builds on schema64: 6.7 keeps reporting the running
+	// request total but also starts reporting a per-second rate
+	// alongside it.
+	schema67 = s.Schema{
+		"concurrent_connections": c.Int("concurrent_connections"),
+		"os":                     schema64["os"],
+		"process":                schema64["process"],
+		"requests": c.Dict("requests", s.Schema{
+			"total":               c.Int("total"),
+			"disconnects":         c.Int("disconnects"),
+			"requests_per_second": c.Float("requests_per_second", s.Optional),
 		}),
-		"usage": c.Dict("usage", s.Schema{
-			"index": c.Str("kibana.index"),
-			"index_pattern": c.Dict("kibana.index_pattern", s.Schema{
-				"total": c.Int("total"),
-			}),
-			"search": c.Dict("kibana.search", s.Schema{
-				"total": c.Int("total"),
-			}),
-			"visualization": c.Dict("kibana.visualization", s.Schema{
-				"total": c.Int("total"),
-			}),
-			"dashboard": c.Dict("kibana.dashboard", s.Schema{
-				"total": c.Int("total"),
+		"response_times": schema64["response_times"],
+	}
+
+	// schema7x covers the 7.x reshape of the stats payload: the heap no
+	// longer reports a size_limit (it reports total/used bytes only),
+	// requests are reported purely as a per-second rate, and
+	// response_times is no longer emitted.
+	schema7x = s.Schema{
+		"concurrent_connections": c.Int("concurrent_connections"),
+		"os": c.Dict("os", s.Schema{
+			"load": c.Dict("load", s.Schema{
+				"1m":  c.Float("1m"),
+				"5m":  c.Float("5m"),
+				"15m": c.Float("15m"),
 			}),
-			"timelion_sheet": c.Dict("kibana.timelion_sheet", s.Schema{
-				"total": c.Int("total"),
+			"memory": c.Dict("memory", s.Schema{
+				"total_in_bytes": c.Int("total_bytes"),
+				"free_in_bytes":  c.Int("free_bytes"),
+				"used_in_bytes":  c.Int("used_bytes"),
 			}),
-			"graph_workspace": c.Dict("kibana.graph_workspace", s.Schema{
-				"total": c.Int("total"),
+			"uptime_in_millis": c.Int("uptime_ms"),
+		}),
+		"process": c.Dict("process", s.Schema{
+			"event_loop_delay": c.Float("event_loop_delay"),
+			"memory": c.Dict("memory", s.Schema{
+				"heap": c.Dict("heap", s.Schema{
+					"total_in_bytes": c.Int("total_bytes"),
+					"used_in_bytes":  c.Int("used_bytes"),
+				}),
 			}),
-			"xpack": s.Object{
-				"reporting": c.Dict("reporting", s.Schema{
-					"available":     c.Bool("available"),
-					"enabled":       c.Bool("enabled"),
-					"browser_type":  c.Str("browser_type"),
-					"_all":          c.Int("all"),
-					"csv":           reportingCsvDict,
-					"printable_pdf": reportingPrintablePdfDict,
-					"status":        reportingStatusDict,
-					"lastDay":       c.Dict("last_day", reportingPeriodSchema, c.DictOptional),
-					"last7Days":     c.Dict("last_7_days", reportingPeriodSchema, c.DictOptional),
-				}, c.DictOptional),
-			},
+			"uptime_in_millis": c.Int("uptime_ms"),
+		}),
+		"requests": c.Dict("requests", s.Schema{
+			"requests_per_second": c.Float("requests_per_second"),
 		}),
 	}
 
@@ -139,6 +171,42 @@ var (
 	}
 )
 
+// metricsSchemaForVersion picks the metrics schema that matches the
+// reporting Kibana instance's version, along with whether the metrics
+// fields are nested under a "metrics" object (true from 6.4 onwards) or
+// live at the top level of the stats payload (6.3 and earlier).
+func metricsSchemaForVersion(version *common.Version) (s.Schema, bool) {
+	switch {
+	case version.Major > 6:
+		return schema7x, true
+	case version.Major == 6 && version.Minor >= 7:
+		return schema67, true
+	case version.Major == 6 && version.Minor >= 4:
+		return schema64, true
+	default:
+		return schema64, false
+	}
+}
+
+func kibanaVersion(data map[string]interface{}) (*common.Version, error) {
+	kibana, ok := data["kibana"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'kibana' field not found in stats API response")
+	}
+
+	versionString, ok := kibana["version"].(string)
+	if !ok {
+		return nil, fmt.Errorf("'kibana.version' field not found in stats API response")
+	}
+
+	version, err := common.NewVersion(versionString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Kibana version %q: %w", versionString, err)
+	}
+
+	return version, nil
+}
+
 func eventMappingXPack(r mb.ReporterV2, intervalMs int64, content []byte) error {
 	var data map[string]interface{}
 	err := json.Unmarshal(content, &data)
@@ -147,13 +215,44 @@ func eventMappingXPack(r mb.ReporterV2, intervalMs int64, content []byte) error
 		return err
 	}
 
-	kibanaStatsFields, err := schemaXPackMonitoring.Apply(data)
+	version, err := kibanaVersion(data)
+	if err != nil {
+		r.Error(err)
+		return err
+	}
+
+	metricsSchema, wrapped := metricsSchemaForVersion(version)
+
+	metricsSource := data
+	if wrapped {
+		metrics, ok := data["metrics"].(map[string]interface{})
+		if !ok {
+			return elastic.ReportErrorForMissingField("metrics", elastic.Kibana, r)
+		}
+		metricsSource = metrics
+	}
+
+	kibanaStatsFields, err := commonXPackSchema.Apply(data)
+	if err != nil {
+		r.Error(err)
+		return err
+	}
+
+	usageFields, err := mapUsage(data)
+	if err != nil {
+		r.Error(err)
+		return err
+	}
+	kibanaStatsFields["usage"] = usageFields
+
+	metricsFields, err := metricsSchema.Apply(metricsSource)
 	if err != nil {
 		r.Error(err)
 		return err
 	}
+	kibanaStatsFields.DeepUpdate(metricsFields)
 
-	process, ok := data["process"].(map[string]interface{})
+	process, ok := metricsSource["process"].(map[string]interface{})
 	if !ok {
 		return elastic.ReportErrorForMissingField("process", elastic.Kibana, r)
 	}
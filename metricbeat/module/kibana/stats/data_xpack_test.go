@@ -0,0 +1,93 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stats
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/libbeat/common"
+	mbtest "github.com/elastic/beats/metricbeat/mb/testing"
+)
+
+func TestEventMappingXPack(t *testing.T) {
+	cases := []struct {
+		file            string
+		wantConnections int64
+		wantHeapLimit   bool
+		wantReqTotal    bool
+		wantRespTimes   bool
+	}{
+		{"stats.6.3.json", 11, true, true, true},
+		{"stats.6.4.json", 13, true, true, true},
+		{"stats.6.7.json", 9, true, true, true},
+		{"stats.7.0.json", 5, false, false, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.file, func(t *testing.T) {
+			content, err := ioutil.ReadFile("./_meta/test/" + c.file)
+			assert.NoError(t, err)
+
+			reporter := &mbtest.CapturingReporterV2{}
+			err = eventMappingXPack(reporter, 10000, content)
+			assert.NoError(t, err)
+			assert.Empty(t, reporter.GetErrors())
+
+			events := reporter.GetEvents()
+			assert.Len(t, events, 1)
+
+			kibanaStats, err := events[0].RootFields.GetValue("kibana_stats")
+			assert.NoError(t, err)
+			stats := kibanaStats.(common.MapStr)
+
+			connections, err := stats.GetValue("concurrent_connections")
+			assert.NoError(t, err)
+			assert.EqualValues(t, c.wantConnections, connections)
+
+			_, err = stats.GetValue("process.memory.heap.size_limit")
+			if c.wantHeapLimit {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+
+			_, err = stats.GetValue("requests.total")
+			if c.wantReqTotal {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+
+			_, err = stats.GetValue("response_times")
+			if c.wantRespTimes {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+
+			_, err = stats.GetValue("process.memory.resident_set_size_in_bytes")
+			assert.NoError(t, err)
+
+			_, err = stats.GetValue("usage.index_pattern.total")
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stats
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/elastic/beats/libbeat/common/cfgwarn"
+	"github.com/elastic/beats/metricbeat/helper"
+	"github.com/elastic/beats/metricbeat/mb"
+	"github.com/elastic/beats/metricbeat/mb/parse"
+)
+
+const (
+	defaultScheme = "http"
+	defaultPath   = "/api/status"
+	settingsPath  = "/api/settings"
+)
+
+var hostParser = parse.URLHostParserBuilder{
+	DefaultScheme: defaultScheme,
+	DefaultPath:   defaultPath,
+}.Build()
+
+func init() {
+	mb.Registry.MustAddMetricSet("kibana", "stats", New,
+		mb.WithHostParser(hostParser),
+	)
+}
+
+// MetricSet fetches Kibana stats and, depending on xpack.enabled, reports
+// either the X-Pack monitoring document(s) or a flat kibana.stats.*
+// event. In X-Pack mode it also emits a companion kibana_settings
+// monitoring document, the way the Kibana monitoring UI expects.
+type MetricSet struct {
+	mb.BaseMetricSet
+	http         *helper.HTTP
+	settingsHTTP *helper.HTTP
+	XPackEnabled bool
+}
+
+// deriveSettingsURI rewrites the stats endpoint's URI into the settings
+// endpoint's URI by swapping the trailing "/api/status" path segment for
+// "/api/settings", preserving any custom base path the user configured
+// (e.g. Kibana served behind a reverse proxy). It errors out rather than
+// silently falling back to the stats URI if the stats path doesn't end
+// in the expected suffix.
+func deriveSettingsURI(statsURI string) (string, error) {
+	u, err := url.Parse(statsURI)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse stats URI %q: %w", statsURI, err)
+	}
+
+	if !strings.HasSuffix(u.Path, defaultPath) {
+		return "", fmt.Errorf("cannot derive settings URI: stats path %q does not end in %q", u.Path, defaultPath)
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, defaultPath) + settingsPath
+	return u.String(), nil
+}
+
+// New creates a new MetricSet for the kibana stats metricset.
+func New(base mb.BaseMetricSet) (mb.MetricSet, error) {
+	cfgwarn.Beta("The kibana stats metricset is beta")
+
+	config := struct {
+		XPackEnabled bool `config:"xpack.enabled"`
+	}{
+		XPackEnabled: false,
+	}
+
+	if err := base.Module().UnpackConfig(&config); err != nil {
+		return nil, err
+	}
+
+	http, err := helper.NewHTTP(base)
+	if err != nil {
+		return nil, err
+	}
+
+	// The settings endpoint is only ever fetched in X-Pack mode, so only
+	// build it there: deriveSettingsURI intentionally errors out when the
+	// configured host path doesn't end in defaultPath (e.g. a
+	// reverse-proxied Kibana with a custom base path), and that shouldn't
+	// break the metricset for non-XPack users who never use it.
+	var settingsHTTP *helper.HTTP
+	if config.XPackEnabled {
+		settingsHTTP, err = helper.NewHTTP(base)
+		if err != nil {
+			return nil, err
+		}
+		settingsURI, err := deriveSettingsURI(settingsHTTP.GetURI())
+		if err != nil {
+			return nil, err
+		}
+		settingsHTTP.SetURI(settingsURI)
+	}
+
+	return &MetricSet{
+		BaseMetricSet: base,
+		http:          http,
+		settingsHTTP:  settingsHTTP,
+		XPackEnabled:  config.XPackEnabled,
+	}, nil
+}
+
+// Fetch methods implements the data gathering and data conversion to the
+// right format. It publishes the event which is then forwarded to the
+// output. Errors are reported through r.Error.
+func (m *MetricSet) Fetch(r mb.ReporterV2) {
+	content, err := m.http.FetchContent()
+	if err != nil {
+		r.Error(err)
+		return
+	}
+
+	intervalMs := m.Module().Config().Period.Nanoseconds() / 1000 / 1000
+
+	if m.XPackEnabled {
+		if err := eventMappingXPack(r, intervalMs, content); err != nil {
+			r.Error(err)
+		}
+
+		settingsContent, err := m.settingsHTTP.FetchContent()
+		if err != nil {
+			r.Error(err)
+			return
+		}
+		if err := eventMappingSettings(r, intervalMs, settingsContent); err != nil {
+			r.Error(err)
+		}
+		return
+	}
+
+	if err := eventMapping(r, content); err != nil {
+		r.Error(err)
+	}
+}
@@ -0,0 +1,37 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveSettingsURI(t *testing.T) {
+	uri, err := deriveSettingsURI("http://localhost:5601/api/status")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:5601/api/settings", uri)
+
+	uri, err = deriveSettingsURI("http://localhost:5601/proxy/kibana/api/status")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:5601/proxy/kibana/api/settings", uri)
+
+	_, err = deriveSettingsURI("http://localhost:5601/custom/path")
+	assert.Error(t, err)
+}